@@ -0,0 +1,134 @@
+/*
+Copyright © 2022 Joe Searcy <joe@twr.io>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FORMAT_JSON = "json"
+	FORMAT_YAML = "yaml"
+	FORMAT_TEXT = "text"
+)
+
+var (
+	responseTemplatePath string
+	responseTemplate     *template.Template
+	templateMutex        sync.Mutex
+)
+
+// loadResponseTemplate parses the file at path as a Go text/template, to be rendered
+// on every "/data" request in place of the built-in JSON/YAML/plaintext formats.
+func loadResponseTemplate(path string) (*template.Template, error) {
+	return template.ParseFiles(path)
+}
+
+// resolveFormat determines which representation to render a "/data" response in,
+// preferring an explicit "?format=" query parameter over the Accept header.
+func resolveFormat(req *http.Request) string {
+	if q := strings.ToLower(req.URL.Query().Get("format")); q != "" {
+		switch q {
+		case "yaml", "yml":
+			return FORMAT_YAML
+		case "text", "plain", "txt":
+			return FORMAT_TEXT
+		case "json":
+			return FORMAT_JSON
+		}
+	}
+
+	accept := strings.ToLower(req.Header.Get("Accept"))
+	switch {
+	case strings.Contains(accept, "yaml"):
+		return FORMAT_YAML
+	case strings.Contains(accept, "text/plain"):
+		return FORMAT_TEXT
+	default:
+		return FORMAT_JSON
+	}
+}
+
+// writeDataResponse renders data (a map[string]string for a single source, or a
+// map[string]map[string]string for the composite "/data" route) in the format
+// requested by req, or through --response-template if one is configured.
+// singleKey, when non-empty, is the configured key for a single-key source; in
+// text format this returns just that key's raw value with no wrapping.
+func writeDataResponse(w http.ResponseWriter, req *http.Request, data interface{}, singleKey string) {
+	if responseTemplate != nil {
+		templateMutex.Lock()
+		defer templateMutex.Unlock()
+
+		if err := responseTemplate.Execute(w, data); err != nil {
+			logger.Error("failed to render response template", "error", err)
+			http.Error(w, "failed to render response template", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	switch resolveFormat(req) {
+	case FORMAT_YAML:
+		w.Header().Set("Content-Type", "application/yaml")
+		enc := yaml.NewEncoder(w)
+		if err := enc.Encode(data); err != nil {
+			logger.Error("failed to encode yaml response", "error", err)
+		}
+		// yaml.Encoder buffers internally; Close flushes it and must be called or the
+		// response body can come back truncated.
+		if err := enc.Close(); err != nil {
+			logger.Error("failed to close yaml encoder", "error", err)
+		}
+
+	case FORMAT_TEXT:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writePlainText(w, data, singleKey)
+
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(data)
+	}
+}
+
+// writePlainText renders data as text/plain. When singleKey is set and data is a
+// single source's map[string]string, the raw value is written with no wrapping at
+// all (e.g. for serving a CA bundle or PEM directly to curl/wget). Otherwise each
+// key is written as a "key: value" line, or as a "name.key: value" line for the
+// composite map of all sources.
+func writePlainText(w http.ResponseWriter, data interface{}, singleKey string) {
+	switch typed := data.(type) {
+	case map[string]string:
+		if singleKey != "" {
+			w.Write([]byte(typed[singleKey]))
+			return
+		}
+		for key, value := range typed {
+			w.Write([]byte(key + ": " + value + "\n"))
+		}
+
+	case map[string]map[string]string:
+		for name, values := range typed {
+			for key, value := range values {
+				w.Write([]byte(name + "." + key + ": " + value + "\n"))
+			}
+		}
+	}
+}
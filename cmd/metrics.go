@@ -0,0 +1,117 @@
+/*
+Copyright © 2022 Joe Searcy <joe@twr.io>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cm2http_http_requests_total",
+		Help: "Total number of HTTP requests handled, by route and status code",
+	}, []string{"route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cm2http_http_request_duration_seconds",
+		Help:    "Latency of HTTP requests, by route",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route"})
+
+	dataKeysServed = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cm2http_data_keys_served",
+		Help: "Current number of data keys served across all sources",
+	})
+
+	watchEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cm2http_watch_events_total",
+		Help: "Total number of ConfigMap/Secret watch events observed, by event type",
+	}, []string{"type"})
+
+	requestCounter atomic.Int64
+)
+
+// nextRequestID returns a small, monotonically increasing identifier used to correlate
+// a request's log lines. It isn't globally unique across restarts, only locally useful.
+func nextRequestID() string {
+	return strconv.FormatInt(requestCounter.Add(1), 10)
+}
+
+// statusRecorder wraps a http.ResponseWriter so the status code written by a handler
+// can be observed after the fact for logging/metrics purposes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// instrumentHandler wraps a route handler with Prometheus metrics and structured
+// access logging, so every route gets the same observability for free. Health/ready
+// checks are logged at debug level to match the old "only log healthz at debug" behavior.
+func instrumentHandler(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		requestID := nextRequestID()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next(recorder, req)
+
+		duration := time.Since(start)
+		httpRequestsTotal.WithLabelValues(route, strconv.Itoa(recorder.status)).Inc()
+		httpRequestDuration.WithLabelValues(route).Observe(duration.Seconds())
+
+		level := slog.LevelInfo
+		if route == "/healthz" || route == "/readyz" {
+			level = slog.LevelDebug
+		}
+
+		logger.Log(req.Context(), level, "endpoint called",
+			"request_id", requestID,
+			"method", req.Method,
+			"path", req.URL.Path,
+			"status", recorder.status,
+			"duration", duration.String(),
+			"user_agent", req.Header.Get("User-Agent"),
+		)
+	}
+}
+
+// updateDataKeysGauge recomputes the total number of data keys being served across
+// every registered source.
+func updateDataKeysGauge() {
+	total := 0
+
+	sourcesMutex.RLock()
+	for _, cfg := range sources {
+		cfg.mutex.Lock()
+		total += len(cfg.data)
+		cfg.mutex.Unlock()
+	}
+	sourcesMutex.RUnlock()
+
+	dataKeysServed.Set(float64(total))
+}
@@ -17,46 +17,101 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"twr.dev/cm2http/pkg/kube"
 )
 
 const (
 	POD_ENV     = "CM2HTTP_POD_NAME"
 	CLUSTER_ENV = "CM2HTTP_CLUSTER_NAME"
+
+	KIND_CONFIGMAP = "configmap"
+	KIND_SECRET    = "secret"
 )
 
 var (
-	cmClient       *cmConfig
-	cfgFile        string
-	cmName         string
-	cmNamespace    string
-	cmKey          string
-	kubeconfig     string
-	kubeContext    string
-	logLevel       string
-	cmOptions      metav1.ListOptions
-	defaultCMValue map[string]string
+	k8sClient       kubernetes.Interface
+	cfgFile         string
+	cmName          string
+	cmNamespace     string
+	cmKey           string
+	kubeconfig      string
+	kubeContext     string
+	logLevel        string
+	sourceFlags     []string
+	resyncInterval  time.Duration
+	listenAddress   string
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+
+	// sources holds every configured source, keyed by its name
+	sources = map[string]*sourceConfig{}
+	// sourcesMutex guards additions/removals from the sources map itself
+	sourcesMutex sync.RWMutex
+
+	// syncedSources tracks which sources have completed their initial informer sync
+	syncedSources = map[string]bool{}
+	syncedMutex   sync.RWMutex
+
+	// logger is the structured logger used throughout the app, configured from --log-level
+	logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	// reconnectBackoff is the backoff runInformerForSource and watchTLSSecret pass to
+	// retry.OnError. Unlike retry.DefaultBackoff (a handful of short steps meant for
+	// optimistic-concurrency retries), its steps grow up to a 2 minute cap, and those
+	// loops re-arm it each time its steps are exhausted.
+	reconnectBackoff = wait.Backoff{
+		Duration: 1 * time.Second,
+		Factor:   2.0,
+		Jitter:   0.1,
+		Steps:    8,
+		Cap:      2 * time.Minute,
+	}
+
+	// legacySource is true when no --source flag or "sources" config was given, so the
+	// single configured source is synthesized from the legacy --configmap-* flags. In
+	// that mode "/data" keeps serving the pre-multi-source flat shape instead of the
+	// {name: {...}} composite, so existing deployments see no response-shape change.
+	legacySource bool
 )
 
-type cmConfig struct {
-	k8sInterface kubernetes.Interface
-	mutex        *sync.Mutex
-	data         map[string]string
+// sourceSpec describes where a single piece of served data comes from
+type sourceSpec struct {
+	Name      string `mapstructure:"name"`
+	Kind      string `mapstructure:"kind"`
+	Namespace string `mapstructure:"namespace"`
+	Resource  string `mapstructure:"resource"`
+	Key       string `mapstructure:"key"`
+}
+
+// sourceConfig tracks the live data and sync primitives for a single source
+type sourceConfig struct {
+	spec  sourceSpec
+	mutex *sync.Mutex
+	data  map[string]string
 }
 
 type infoResponse struct {
@@ -68,8 +123,8 @@ type infoResponse struct {
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "cm2http",
-	Short: "A utility to discover and serve the data from a Kubernetes configMap via HTTP",
-	Long:  `A utility to discover and serve the data from a Kubernetes configMap via HTTP`,
+	Short: "A utility to discover and serve the data from Kubernetes configMaps/secrets via HTTP",
+	Long:  `A utility to discover and serve the data from Kubernetes configMaps/secrets via HTTP`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	Run: func(cmd *cobra.Command, args []string) {
@@ -79,32 +134,88 @@ var rootCmd = &cobra.Command{
 			fmt.Printf("Error parsing flag input: %v", err)
 		}
 
-		// Set default value for cert
-		defaultCMValue = make(map[string]string)
+		logger = newLogger(logLevel)
+
+		if responseTemplatePath != "" {
+			tmpl, err := loadResponseTemplate(responseTemplatePath)
+			if err != nil {
+				logger.Error("unable to parse --response-template", "path", responseTemplatePath, "error", err)
+				os.Exit(1)
+			}
+			responseTemplate = tmpl
+		}
+
+		specs, legacy, err := loadSourceSpecs(cmd)
+		if err != nil {
+			logger.Error("unable to load source configuration", "error", err)
+			os.Exit(1)
+		}
+		legacySource = legacy
+
+		// Build a context that's cancelled on SIGINT/SIGTERM, so the informers and the
+		// HTTP server can both shut down cleanly instead of being killed mid-request.
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
 
 		// Setup the Kubernetes Client
 		client, err := kube.CreateKubeClient(kubeconfig, kubeContext)
 		if err != nil {
-			message := fmt.Sprintf("ERROR: Unable to generate kubernetes client: %v\n", err)
-			panic(message)
+			logger.Error("unable to generate kubernetes client", "error", err)
+			os.Exit(1)
 		}
+		k8sClient = client
 
-		// Setup initial info
-		cmClient = &cmConfig{}
-		cmClient.k8sInterface = client
-		cmClient.mutex = &sync.Mutex{}
+		// Register each source and start its informer
+		for _, spec := range specs {
+			registerSource(ctx, spec)
+		}
 
-		// Setup configmap watcher
-		go watchConfigMap(cmClient, cmd)
+		// Handle routes
+		http.HandleFunc("/info", instrumentHandler("/info", infoRouteHandler))
+		http.HandleFunc("/healthz", instrumentHandler("/healthz", healthzRouteHandler))
+		http.HandleFunc("/readyz", instrumentHandler("/readyz", readyzRouteHandler))
+		http.HandleFunc("/data", instrumentHandler("/data", cmDataRouteHandler))
+		http.Handle("/metrics", promhttp.Handler())
 
-		// Handel routes
-		http.HandleFunc("/info", infoRouteHandler)
-		http.HandleFunc("/healthz", healthzRouteHandler)
-		http.HandleFunc("/readyz", healthzRouteHandler)
-		http.HandleFunc("/data", cmDataRouteHandler)
+		tlsConfig, err := buildTLSConfig(ctx)
+		if err != nil {
+			logger.Error("unable to configure TLS", "error", err)
+			os.Exit(1)
+		}
 
-		fmt.Printf("Listening on port 5555\n")
-		http.ListenAndServe(":5555", nil)
+		server := &http.Server{
+			Addr:         listenAddress,
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  readTimeout,
+			WriteTimeout: writeTimeout,
+			IdleTimeout:  idleTimeout,
+		}
+
+		go func() {
+			<-ctx.Done()
+
+			logger.Info("shutdown signal received, draining connections", "timeout", shutdownTimeout.String())
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				logger.Error("graceful shutdown did not complete cleanly", "error", err)
+			}
+		}()
+
+		var serveErr error
+		if tlsConfig != nil {
+			logger.Info("listening", "address", listenAddress, "tls", true, "mtls", tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert)
+			serveErr = server.ListenAndServeTLS("", "")
+		} else {
+			logger.Info("listening", "address", listenAddress, "tls", false)
+			serveErr = server.ListenAndServe()
+		}
+
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			logger.Error("server exited unexpectedly", "error", serveErr)
+		}
 
 	},
 }
@@ -130,12 +241,24 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	//rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	rootCmd.Flags().StringVar(&cmName, "configmap-name", "kube-root-ca.crt", "name of a configmap")
-	rootCmd.Flags().StringVar(&cmNamespace, "configmap-namespace", "", "name of the namespace where the configmap is located")
-	rootCmd.Flags().StringVar(&cmKey, "configmap-key", "", "name of a specific key in the configmap")
+	rootCmd.Flags().StringVar(&cmName, "configmap-name", "kube-root-ca.crt", "name of a configmap. Ignored if --source is used")
+	rootCmd.Flags().StringVar(&cmNamespace, "configmap-namespace", "", "name of the namespace where the configmap is located. Ignored if --source is used")
+	rootCmd.Flags().StringVar(&cmKey, "configmap-key", "", "name of a specific key in the configmap. Ignored if --source is used")
+	rootCmd.Flags().StringArrayVar(&sourceFlags, "source", nil, "a source to serve in the form name=kind/namespace/resource[:key], where kind is \"configmap\" or \"secret\". May be repeated to serve multiple sources, each under its own /data/{name} route")
 	rootCmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "name of the kubeconfig file to use. Leave blank for default/in-cluster")
 	rootCmd.Flags().StringVar(&kubeContext, "context", "", "name of the kubeconfig context to use. Leave blank for default")
-	rootCmd.Flags().StringVar(&logLevel, "log-level", "info", "logging level. One of \"info\" or \"debug\"")
+	rootCmd.Flags().StringVar(&logLevel, "log-level", "info", "logging level. One of \"debug\", \"info\", \"warn\", or \"error\"")
+	rootCmd.Flags().DurationVar(&resyncInterval, "resync-interval", 10*time.Minute, "how often the informer for each source performs a full resync against the API server")
+	rootCmd.Flags().StringVar(&responseTemplatePath, "response-template", "", "path to a Go text/template file used to render every \"/data\" response, instead of JSON/YAML/plaintext (e.g. for .env, ini, or Nginx snippet output)")
+	rootCmd.Flags().StringVar(&tlsCertFile, "tls-cert-file", "", "path to a TLS certificate file. Serves over HTTPS if set (or if --tls-from-secret is set)")
+	rootCmd.Flags().StringVar(&tlsKeyFile, "tls-key-file", "", "path to the TLS private key file matching --tls-cert-file")
+	rootCmd.Flags().StringVar(&tlsClientCAFile, "tls-client-ca-file", "", "path to a CA bundle used to verify client certificates. Enables mTLS on the HTTPS listener")
+	rootCmd.Flags().StringVar(&tlsFromSecret, "tls-from-secret", "", "namespace/name of a Secret (with \"tls.crt\"/\"tls.key\" keys) to serve HTTPS from, hot-reloaded whenever the Secret changes")
+	rootCmd.Flags().StringVar(&listenAddress, "listen-address", ":5555", "address for the HTTP(S) server to listen on")
+	rootCmd.Flags().DurationVar(&readTimeout, "read-timeout", 5*time.Second, "maximum duration for reading the entire request, including the body")
+	rootCmd.Flags().DurationVar(&writeTimeout, "write-timeout", 10*time.Second, "maximum duration before timing out writes of the response")
+	rootCmd.Flags().DurationVar(&idleTimeout, "idle-timeout", 120*time.Second, "maximum amount of time to wait for the next request on a keep-alive connection")
+	rootCmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 15*time.Second, "grace period to let in-flight requests finish before the server shuts down")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -162,17 +285,163 @@ func initConfig() {
 	}
 }
 
-// cmDataRouteHandler handles calls for the "/data" route
-// This route reads the data key(s) from a configMap and outputs the data in JSON format
+// loadSourceSpecs builds the list of sources to serve, preferring (in order)
+// repeated --source flags, a "sources" list in the viper config file, and
+// finally falling back to the legacy --configmap-name/-namespace/-key flags.
+// The returned bool is true when the legacy fallback was used.
+func loadSourceSpecs(cmd *cobra.Command) ([]sourceSpec, bool, error) {
+	if len(sourceFlags) > 0 {
+		specs := make([]sourceSpec, 0, len(sourceFlags))
+		for _, raw := range sourceFlags {
+			spec, err := parseSourceFlag(raw)
+			if err != nil {
+				return nil, false, err
+			}
+			specs = append(specs, spec)
+		}
+		if err := validateSourceNames(specs); err != nil {
+			return nil, false, err
+		}
+		return specs, false, nil
+	}
+
+	if viper.IsSet("sources") {
+		var specs []sourceSpec
+		if err := viper.UnmarshalKey("sources", &specs); err != nil {
+			return nil, false, fmt.Errorf("unable to parse \"sources\" from config file: %w", err)
+		}
+		for i := range specs {
+			if specs[i].Kind == "" {
+				specs[i].Kind = KIND_CONFIGMAP
+			}
+		}
+		if err := validateSourceNames(specs); err != nil {
+			return nil, false, err
+		}
+		return specs, false, nil
+	}
+
+	// Legacy single-configmap behavior
+	return []sourceSpec{
+		{
+			Name:      "default",
+			Kind:      KIND_CONFIGMAP,
+			Namespace: cmNamespace,
+			Resource:  cmName,
+			Key:       cmKey,
+		},
+	}, true, nil
+}
+
+// validateSourceNames rejects empty or duplicate source names before they can reach
+// the sources map or http.HandleFunc, where a duplicate would otherwise panic the
+// process at startup ("multiple registrations for /data/x") instead of failing cleanly.
+func validateSourceNames(specs []sourceSpec) error {
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return fmt.Errorf("source name must not be empty")
+		}
+		if seen[spec.Name] {
+			return fmt.Errorf("duplicate source name %q", spec.Name)
+		}
+		seen[spec.Name] = true
+	}
+	return nil
+}
+
+// parseSourceFlag parses a --source flag value of the form
+// "name=kind/namespace/resource[:key]" into a sourceSpec.
+func parseSourceFlag(raw string) (sourceSpec, error) {
+	nameAndRest := strings.SplitN(raw, "=", 2)
+	if len(nameAndRest) != 2 {
+		return sourceSpec{}, fmt.Errorf("invalid --source %q: expected format name=kind/namespace/resource[:key]", raw)
+	}
+
+	parts := strings.SplitN(nameAndRest[1], "/", 3)
+	if len(parts) != 3 {
+		return sourceSpec{}, fmt.Errorf("invalid --source %q: expected format name=kind/namespace/resource[:key]", raw)
+	}
+
+	resourceAndKey := strings.SplitN(parts[2], ":", 2)
+
+	spec := sourceSpec{
+		Name:      nameAndRest[0],
+		Kind:      strings.ToLower(parts[0]),
+		Namespace: parts[1],
+		Resource:  resourceAndKey[0],
+	}
+	if len(resourceAndKey) == 2 {
+		spec.Key = resourceAndKey[1]
+	}
+
+	if spec.Kind != KIND_CONFIGMAP && spec.Kind != KIND_SECRET {
+		return sourceSpec{}, fmt.Errorf("invalid --source %q: kind must be %q or %q", raw, KIND_CONFIGMAP, KIND_SECRET)
+	}
+
+	return spec, nil
+}
+
+// registerSource stores the source under its name and starts its informer
+func registerSource(ctx context.Context, spec sourceSpec) {
+	cfg := &sourceConfig{
+		spec:  spec,
+		mutex: &sync.Mutex{},
+		data:  make(map[string]string),
+	}
+
+	sourcesMutex.Lock()
+	sources[spec.Name] = cfg
+	sourcesMutex.Unlock()
+
+	route := fmt.Sprintf("/data/%s", spec.Name)
+	http.HandleFunc(route, instrumentHandler(route, makeSourceRouteHandler(cfg)))
+
+	go runInformerForSource(ctx, cfg)
+}
+
+// cmDataRouteHandler handles calls for the "/data" route.
+// In legacy mode (no --source/"sources" config given) this serves the single
+// configured source's flat map exactly as before multi-source support existed.
+// Otherwise it returns a composite object of every configured source, keyed by
+// source name, rendered by writeDataResponse in the requested format.
 func cmDataRouteHandler(w http.ResponseWriter, req *http.Request) {
 
-	// Print current CA Cert
-	cmClient.mutex.Lock()
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(cmClient.data)
-	cmClient.mutex.Unlock()
+	if legacySource {
+		sourcesMutex.RLock()
+		cfg := sources["default"]
+		sourcesMutex.RUnlock()
+
+		cfg.mutex.Lock()
+		data := cfg.data
+		cfg.mutex.Unlock()
 
-	fmt.Printf("%q\tendpoint called [ Method: %q, Protocol: %q, User Agent: %q, Namespace: %q, ConfigMap: %q, Key: %q ]\n", req.RequestURI, req.Method, req.Proto, req.Header.Get("User-Agent"), cmNamespace, cmName, cmKey)
+		writeDataResponse(w, req, data, cfg.spec.Key)
+		return
+	}
+
+	composite := make(map[string]map[string]string)
+
+	sourcesMutex.RLock()
+	for name, cfg := range sources {
+		cfg.mutex.Lock()
+		composite[name] = cfg.data
+		cfg.mutex.Unlock()
+	}
+	sourcesMutex.RUnlock()
+
+	writeDataResponse(w, req, composite, "")
+}
+
+// makeSourceRouteHandler builds a "/data/{name}" handler bound to a single source's data
+func makeSourceRouteHandler(cfg *sourceConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cfg.mutex.Lock()
+		data := cfg.data
+		cfg.mutex.Unlock()
+
+		writeDataResponse(w, req, data, cfg.spec.Key)
+	}
 }
 
 // infoRouteHandler handles calls for the "/info" route
@@ -182,140 +451,231 @@ func infoRouteHandler(w http.ResponseWriter, req *http.Request) {
 	var responseInfo infoResponse
 
 	// Set Response Body
-	cmClient.mutex.Lock()
 	responseInfo.Cluster = os.Getenv(CLUSTER_ENV)
 	responseInfo.Pod = os.Getenv(POD_ENV)
 	responseInfo.Datetime = time.Now()
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(responseInfo)
-	cmClient.mutex.Unlock()
-
-	fmt.Printf("%q\tendpoint called [ Method: %q, Protocol: %q, User Agent: %q, Namespace: %q, ConfigMap: %q, Key: %q ]\n", req.RequestURI, req.Method, req.Proto, req.Header.Get("User-Agent"), cmNamespace, cmName, cmKey)
 }
 
-// healthzRouteHandler/readyzRouteHandler handles calls for the "/healthz" and "/readyz" routes
-// This route outputs the current health/ready status of the app
+// healthzRouteHandler handles calls for the "/healthz" route.
+// Unlike readyz, healthz reports healthy as soon as the process is serving requests.
 func healthzRouteHandler(w http.ResponseWriter, req *http.Request) {
+	writeStatusResponse(w, "healthy", true)
+}
 
-	response := make(map[string]string)
-
-	// Set route type based on whether it's called as "/readyz" or "/healthz"
-	routeType := "healthy"
-	if req.RequestURI == "/readyz" {
-		routeType = "ready"
+// readyzRouteHandler handles calls for the "/readyz" route.
+// This only reports ready once every source's informer has completed its initial sync,
+// so Kubernetes doesn't route traffic to a pod that would serve empty data.
+func readyzRouteHandler(w http.ResponseWriter, req *http.Request) {
+	ready := allSourcesSynced()
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
 	}
+	writeStatusResponse(w, "ready", ready)
+}
 
-	response[routeType] = "true"
+// writeStatusResponse writes a {"<key>": "<value>"} JSON body used by the healthz/readyz routes
+func writeStatusResponse(w http.ResponseWriter, key string, value bool) {
+	response := map[string]string{key: fmt.Sprintf("%t", value)}
 
-	// Set Response Body
-	cmClient.mutex.Lock()
 	w.Header().Set("Content-Type", "application/json")
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
 		panic("Unable to marshal response body to JSON" + err.Error())
 	}
 	w.Write(jsonResponse)
-	cmClient.mutex.Unlock()
+}
 
-	// Only log calls to "/healthz" and "/readyz" if debug log-level is selected
-	if strings.ToLower(logLevel) == "debug" {
-		fmt.Printf("%q\tendpoint called [ Method: %q, Protocol: %q, User Agent: %q, Namespace: %q, ConfigMap: %q, Key: %q ]\n", req.RequestURI, req.Method, req.Proto, req.Header.Get("User-Agent"), cmNamespace, cmName, cmKey)
+// runInformerForSource runs the shared informer backing a single source until ctx is
+// cancelled. syncSource is wrapped in retry.OnError with reconnectBackoff, and an outer
+// loop re-arms the backoff each time its steps are exhausted; but syncSource only returns
+// an error once ctx is cancelled, and the retry predicate stops on a cancelled ctx, so it
+// is not re-run. Failed list/watch calls and dropped connections are retried by the
+// informer's own reflector for the life of the process.
+func runInformerForSource(ctx context.Context, cfg *sourceConfig) {
+	for ctx.Err() == nil {
+		err := retry.OnError(reconnectBackoff, func(error) bool { return ctx.Err() == nil }, func() error {
+			return syncSource(ctx, cfg)
+		})
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		logger.Warn("informer sync still failing, continuing to retry", "source", cfg.spec.Name, "error", err)
 	}
 }
 
-// watchConfigMap to stand up a watcher for the configMap
-func watchConfigMap(cmClient *cmConfig, cmd *cobra.Command) {
+// syncSource builds a namespace-scoped SharedInformerFactory for a source's kind,
+// waits for its initial cache sync, and then blocks serving events until ctx is
+// cancelled. It returns an error only when ctx is cancelled before the initial sync
+// completes; list/watch failures are retried inside the informer and do not end it.
+func syncSource(ctx context.Context, cfg *sourceConfig) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(k8sClient, resyncInterval, informers.WithNamespace(cfg.spec.Namespace))
+
+	var informer cache.SharedIndexInformer
+	switch cfg.spec.Kind {
+	case KIND_SECRET:
+		informer = factory.Core().V1().Secrets().Informer()
+	default:
+		informer = factory.Core().V1().ConfigMaps().Informer()
+	}
 
-	// Set options to filter for a single configMap object
-	cmOptions = metav1.SingleObject(metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			watchEventsTotal.WithLabelValues("added").Inc()
+			handleSourceUpdate(cfg, obj, "added")
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			watchEventsTotal.WithLabelValues("modified").Inc()
+			handleSourceUpdate(cfg, newObj, "modified")
+		},
+		DeleteFunc: func(obj interface{}) {
+			watchEventsTotal.WithLabelValues("deleted").Inc()
+			handleSourceDelete(cfg, obj)
+		},
+	})
+
+	stopCh := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(func() { close(stopCh) }) }
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	factory.Start(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		// WaitForCacheSync only gives up once stopCh is closed, which happens when ctx
+		// is cancelled; the retry predicate then ends runInformerForSource's loop.
+		stop()
+		return fmt.Errorf("informer cache for source %q never synced", cfg.spec.Name)
+	}
 
-	// Watch for events on configMap
-	for {
-		watcher, err := cmClient.k8sInterface.CoreV1().ConfigMaps(cmNamespace).Watch(context.TODO(), cmOptions)
-		if err != nil {
-			panic("Unable to create watcher: " + err.Error())
+	markSourceSynced(cfg.spec.Name)
+	logger.Info("informer synced", "source", cfg.spec.Name, "kind", cfg.spec.Kind, "namespace", cfg.spec.Namespace, "resource", cfg.spec.Resource)
+
+	// Block here; the informer keeps running and reconnecting on its own until ctx is cancelled.
+	<-stopCh
+	return nil
+}
+
+// handleSourceUpdate applies the data from an added/modified object to a source, if it
+// matches the object the source was configured to watch.
+func handleSourceUpdate(cfg *sourceConfig, obj interface{}, eventType string) {
+	var data map[string]string
+
+	switch cfg.spec.Kind {
+	case KIND_SECRET:
+		secret, ok := obj.(*corev1.Secret)
+		if !ok || secret.Name != cfg.spec.Resource {
+			return
 		}
+		logger.Info("secret has been "+eventType, "source", cfg.spec.Name, "namespace", cfg.spec.Namespace, "secret", cfg.spec.Resource)
+		data = make(map[string]string, len(secret.Data))
+		for key, value := range secret.Data {
+			data[key] = string(value)
+		}
+	default:
+		cm, ok := obj.(*corev1.ConfigMap)
+		if !ok || cm.Name != cfg.spec.Resource {
+			return
+		}
+		logger.Info("configmap has been "+eventType, "source", cfg.spec.Name, "namespace", cfg.spec.Namespace, "configmap", cfg.spec.Resource)
+		data = cm.Data
+	}
 
-		// Update Serviced Data
-		updateCMData(watcher.ResultChan(), cmClient, cmd)
+	cfg.mutex.Lock()
+	if cfg.spec.Key != "" {
+		if value, ok := data[cfg.spec.Key]; ok {
+			cfg.data = map[string]string{cfg.spec.Key: value}
+		} else {
+			logger.Warn("key not found, serving default value", "source", cfg.spec.Name, "key", cfg.spec.Key)
+		}
+	} else if len(data) >= 1 {
+		cfg.data = data
+	} else {
+		logger.Warn("object has no data keys, serving default value", "source", cfg.spec.Name)
 	}
+	cfg.mutex.Unlock()
+
+	updateDataKeysGauge()
 }
 
-// updateCMData updates the data served upon configMap changes
-func updateCMData(eventChannel <-chan watch.Event, cmClient *cmConfig, cmd *cobra.Command) {
-	// React to incoming events on the channel
-	for {
-		event, open := <-eventChannel
-
-		if open {
-
-			// Parse based on incoming event type
-			switch event.Type {
-
-			// Handle Object added
-			case watch.Added:
-
-				fallthrough
-
-			// Handle object modified
-			case watch.Modified:
-
-				fmt.Printf("Target configmap \"%v/%v\" has been modified\n", cmNamespace, cmName)
-
-				// Update the CM Data
-				cmClient.mutex.Lock()
-				if cm, ok := event.Object.(*corev1.ConfigMap); ok {
-					fmt.Printf("Object retrieved from watcher is of Kind ConfigMap\n")
-					if cmd.Flag("configmap-key").Changed {
-
-						if cmValue, ok := cm.Data[cmKey]; ok {
-							fmt.Printf("%q configMap key specified/or using default, serving single key", cmKey)
-							fmt.Printf("Object retrieved from watcher has target data key %q\n", cmKey)
-							tmpData := make(map[string]string)
-							tmpData[cmKey] = cmValue
-							cmClient.data = tmpData
-							fmt.Printf("Updating Data Served\n")
-						} else {
-							fmt.Printf("Key not found in configMap. Serving default value\n")
-						}
-					} else if len(cm.Data) >= 1 {
-						fmt.Printf("No configMap key specified, serving all data keys")
-						cmClient.data = cm.Data
-						fmt.Printf("Updating Data Served\n")
-					} else {
-						fmt.Printf("ConfigMap has no Data Keys. Serving default value\n")
-					}
-				} else {
-					fmt.Printf("Object retrieved from watcher is not a ConfigMap")
-				}
-				cmClient.mutex.Unlock()
-
-			// Handle object deleted
-			case watch.Deleted:
-
-				fmt.Printf("Target configmap \"%v/%v\" has been deleted\n", cmNamespace, cmName)
-
-				// Fall back to the default value
-				cmClient.mutex.Lock()
-				cmClient.data = defaultCMValue
-				fmt.Printf("Setting default value: %v\n", cmClient.data)
-				cmClient.mutex.Unlock()
-
-			default:
-				// Do nothing
-			}
-		} else {
-			// If eventChannel is closed, it means the server has closed the connection
-			return
+// handleSourceDelete resets a source's served data once its backing object is deleted,
+// ignoring deletions of any other object in the namespace.
+func handleSourceDelete(cfg *sourceConfig, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	var name string
+	switch typed := obj.(type) {
+	case *corev1.Secret:
+		name = typed.Name
+	case *corev1.ConfigMap:
+		name = typed.Name
+	}
+	if name != cfg.spec.Resource {
+		return
+	}
+
+	logger.Info("source backing object has been deleted", "source", cfg.spec.Name, "kind", cfg.spec.Kind, "namespace", cfg.spec.Namespace, "resource", cfg.spec.Resource)
+
+	cfg.mutex.Lock()
+	cfg.data = make(map[string]string)
+	cfg.mutex.Unlock()
+
+	updateDataKeysGauge()
+}
+
+// markSourceSynced records that a source's informer has completed its initial sync
+func markSourceSynced(name string) {
+	syncedMutex.Lock()
+	syncedSources[name] = true
+	syncedMutex.Unlock()
+}
+
+// allSourcesSynced reports whether every registered source has completed its initial sync
+func allSourcesSynced() bool {
+	sourcesMutex.RLock()
+	defer sourcesMutex.RUnlock()
+
+	syncedMutex.RLock()
+	defer syncedMutex.RUnlock()
+
+	for name := range sources {
+		if !syncedSources[name] {
+			return false
 		}
 	}
+	return true
+}
+
+// validLogLevels are the values accepted by --log-level
+var validLogLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
 }
 
 func validateFlagOptions(cmd *cobra.Command) error {
-	if strings.ToLower(cmd.Flag("log-level").Value.String()) != "info" && strings.ToLower(cmd.Flag("log-level").Value.String()) != "debug" {
+	if _, ok := validLogLevels[strings.ToLower(cmd.Flag("log-level").Value.String())]; !ok {
 		errString := fmt.Sprintf("option %q passed to %q flag is not valid. Using default value %q\n", cmd.Flag("log-level").Value.String(), cmd.Flag("log-level").Name, cmd.Flag("log-level").DefValue)
 		return errors.New(errString)
 	}
 
 	return nil
 }
+
+// newLogger builds the structured logger used throughout the app for the given --log-level
+func newLogger(level string) *slog.Logger {
+	slogLevel, ok := validLogLevels[strings.ToLower(level)]
+	if !ok {
+		slogLevel = slog.LevelInfo
+	}
+
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slogLevel}))
+}
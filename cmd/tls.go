@@ -0,0 +1,208 @@
+/*
+Copyright © 2022 Joe Searcy <joe@twr.io>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+var (
+	tlsCertFile     string
+	tlsKeyFile      string
+	tlsClientCAFile string
+	tlsFromSecret   string
+)
+
+// tlsCertStore holds the currently active server certificate behind a GetCertificate
+// callback, so it can be hot-swapped without restarting the listener.
+type tlsCertStore struct {
+	mutex sync.RWMutex
+	cert  *tls.Certificate
+}
+
+func (s *tlsCertStore) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.cert == nil {
+		return nil, fmt.Errorf("no TLS certificate loaded yet")
+	}
+	return s.cert, nil
+}
+
+func (s *tlsCertStore) set(cert tls.Certificate) {
+	s.mutex.Lock()
+	s.cert = &cert
+	s.mutex.Unlock()
+}
+
+// buildTLSConfig assembles the *tls.Config to serve with, or returns nil if no TLS
+// flags were set and the server should stay plain HTTP. The certificate is sourced
+// either from --tls-cert-file/--tls-key-file (loaded once) or --tls-from-secret
+// (hot-reloaded for the life of the process via the same informer machinery used
+// for data sources). --tls-client-ca-file additionally enables mTLS.
+func buildTLSConfig(ctx context.Context) (*tls.Config, error) {
+	if tlsCertFile == "" && tlsFromSecret == "" {
+		if tlsClientCAFile != "" {
+			// Without a cert source there is no HTTPS listener to apply mTLS to, so
+			// silently falling back to plain HTTP would drop client-cert enforcement
+			// the operator explicitly asked for. Fail loudly instead.
+			return nil, fmt.Errorf("--tls-client-ca-file requires --tls-cert-file/--tls-key-file or --tls-from-secret to be set")
+		}
+		return nil, nil
+	}
+
+	store := &tlsCertStore{}
+
+	if tlsFromSecret != "" {
+		namespace, name, err := parseNamespacedName(tlsFromSecret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --tls-from-secret %q: %w", tlsFromSecret, err)
+		}
+		go watchTLSSecret(ctx, namespace, name, store)
+	} else {
+		cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load --tls-cert-file/--tls-key-file: %w", err)
+		}
+		store.set(cert)
+	}
+
+	tlsConfig := &tls.Config{
+		GetCertificate: store.GetCertificate,
+	}
+
+	if tlsClientCAFile != "" {
+		caPEM, err := os.ReadFile(tlsClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read --tls-client-ca-file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("unable to parse any certificates from --tls-client-ca-file %q", tlsClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// parseNamespacedName splits a "namespace/name" flag value in two
+func parseNamespacedName(raw string) (namespace string, name string, err error) {
+	parts := strings.SplitN(raw, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("expected format namespace/name")
+	}
+	return parts[0], parts[1], nil
+}
+
+// watchTLSSecret keeps store up to date with the tls.crt/tls.key data of the named
+// Secret until ctx is cancelled. As in runInformerForSource, syncTLSSecret is wrapped
+// in retry.OnError with reconnectBackoff inside an outer loop, but it only returns an
+// error once ctx is cancelled, so it is not re-run; failed list/watch calls are
+// retried by the informer's own reflector.
+func watchTLSSecret(ctx context.Context, namespace, name string, store *tlsCertStore) {
+	for ctx.Err() == nil {
+		err := retry.OnError(reconnectBackoff, func(error) bool { return ctx.Err() == nil }, func() error {
+			return syncTLSSecret(ctx, namespace, name, store)
+		})
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		logger.Warn("TLS secret sync still failing, continuing to retry", "namespace", namespace, "secret", name, "error", err)
+	}
+}
+
+func syncTLSSecret(ctx context.Context, namespace, name string, store *tlsCertStore) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(k8sClient, resyncInterval, informers.WithNamespace(namespace))
+	informer := factory.Core().V1().Secrets().Informer()
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			applyTLSSecret(obj, name, store)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			applyTLSSecret(newObj, name, store)
+		},
+	})
+
+	stopCh := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(func() { close(stopCh) }) }
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	factory.Start(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		// WaitForCacheSync only gives up once stopCh is closed, which happens when ctx
+		// is cancelled; the retry predicate then ends watchTLSSecret's loop.
+		stop()
+		return fmt.Errorf("informer cache for TLS secret %q/%q never synced", namespace, name)
+	}
+
+	logger.Info("TLS secret informer synced", "namespace", namespace, "secret", name)
+
+	<-stopCh
+	return nil
+}
+
+// applyTLSSecret loads a fresh certificate/key pair into store whenever the watched
+// Secret changes, ignoring any other secrets in the namespace.
+func applyTLSSecret(obj interface{}, name string, store *tlsCertStore) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Name != name {
+		return
+	}
+
+	certPEM, ok := secret.Data["tls.crt"]
+	if !ok {
+		logger.Warn("TLS secret missing tls.crt key, skipping reload", "namespace", secret.Namespace, "secret", name)
+		return
+	}
+
+	keyPEM, ok := secret.Data["tls.key"]
+	if !ok {
+		logger.Warn("TLS secret missing tls.key key, skipping reload", "namespace", secret.Namespace, "secret", name)
+		return
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		logger.Error("failed to parse TLS certificate/key from secret", "namespace", secret.Namespace, "secret", name, "error", err)
+		return
+	}
+
+	store.set(cert)
+	logger.Info("reloaded TLS certificate from secret", "namespace", secret.Namespace, "secret", name)
+}